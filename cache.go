@@ -0,0 +1,92 @@
+package vbb
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is a pluggable response cache for Client. Get reports whether a non-expired value exists
+// for key; Set stores value under key for the given ttl.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used entry once it grows beyond
+// its capacity, in addition to expiring entries after their TTL elapses.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+// NewLRUCache returns an LRUCache holding up to capacity entries
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the cached value for key, if any and not yet expired
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.items, key)
+
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return entry.value, true
+}
+
+// Set stores value under key until ttl elapses, evicting the least recently used entry if the
+// cache is at capacity
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = time.Now().Add(ttl)
+		c.order.MoveToFront(el)
+
+		return
+	}
+
+	el := c.order.PushFront(&lruEntry{
+		key:       key,
+		value:     value,
+		expiresAt: time.Now().Add(ttl),
+	})
+	c.items[key] = el
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package vbb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCacheGetSet(t *testing.T) {
+	c := NewLRUCache(2)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("Get on empty cache returned a value")
+	}
+
+	c.Set("a", []byte("1"), time.Minute)
+
+	got, ok := c.Get("a")
+	if !ok || string(got) != "1" {
+		t.Fatalf("Get(%q) = %q, %v, want %q, true", "a", got, ok, "1")
+	}
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Set("a", []byte("1"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("Get(%q) returned an entry past its TTL", "a")
+	}
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2"), time.Minute)
+
+	// Touching "a" makes "b" the least recently used entry
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("Get(%q) missing right after Set", "a")
+	}
+
+	c.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Get(%q) = ok, want evicted as least recently used", "b")
+	}
+
+	if _, ok := c.Get("a"); !ok {
+		t.Errorf("Get(%q) = not ok, want still present", "a")
+	}
+
+	if _, ok := c.Get("c"); !ok {
+		t.Errorf("Get(%q) = not ok, want present", "c")
+	}
+}
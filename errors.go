@@ -0,0 +1,74 @@
+package vbb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors that APIError matches via errors.Is, so callers can branch on the failure
+// category without inspecting StatusCode themselves.
+var (
+	ErrRateLimited = errors.New("vbb: rate limited")
+	ErrNotFound    = errors.New("vbb: not found")
+	ErrUpstream    = errors.New("vbb: upstream error")
+)
+
+// APIError is returned when v6.vbb.transport.rest responds with a status code >= 400
+type APIError struct {
+	StatusCode int
+	Endpoint   string
+	// HafasCode and HafasMessage are parsed from the response body's code/msg fields, if present
+	HafasCode    string
+	HafasMessage string
+}
+
+func (e *APIError) Error() string {
+	if e.HafasMessage != "" {
+		return fmt.Sprintf("vbb: %s: %d %s", e.Endpoint, e.StatusCode, e.HafasMessage)
+	}
+
+	return fmt.Sprintf("vbb: %s: %d %s", e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Is reports whether target is one of the sentinel errors matching e.StatusCode, so that
+// errors.Is(err, ErrNotFound) works for the error returned by Client methods.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUpstream:
+		return e.StatusCode >= 500
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether a request that failed with e is worth retrying
+func (e *APIError) shouldRetry() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// newAPIError builds an APIError from a non-2xx response, parsing the HAFAS code/msg fields from
+// its JSON body when present.
+func newAPIError(endpoint string, resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Endpoint:   endpoint,
+	}
+
+	var body struct {
+		Code string
+		Msg  string
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil {
+		apiErr.HafasCode = body.Code
+		apiErr.HafasMessage = body.Msg
+	}
+
+	return apiErr
+}
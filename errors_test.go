@@ -0,0 +1,104 @@
+package vbb
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		target     error
+		want       bool
+	}{
+		{"rate limited matches ErrRateLimited", http.StatusTooManyRequests, ErrRateLimited, true},
+		{"not found matches ErrNotFound", http.StatusNotFound, ErrNotFound, true},
+		{"server error matches ErrUpstream", http.StatusBadGateway, ErrUpstream, true},
+		{"not found does not match ErrRateLimited", http.StatusNotFound, ErrRateLimited, false},
+		{"client error does not match ErrUpstream", http.StatusBadRequest, ErrUpstream, false},
+		{"unrelated sentinel never matches", http.StatusNotFound, errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &APIError{StatusCode: tt.statusCode}
+			if got := errors.Is(err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%+v, %v) = %v, want %v", err, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIErrorError(t *testing.T) {
+	withMessage := &APIError{StatusCode: http.StatusNotFound, Endpoint: "/locations", HafasMessage: "no results"}
+	if got, want := withMessage.Error(), "vbb: /locations: 404 no results"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	withoutMessage := &APIError{StatusCode: http.StatusNotFound, Endpoint: "/locations"}
+	if got, want := withoutMessage.Error(), "vbb: /locations: 404 Not Found"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestAPIErrorShouldRetry(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       bool
+	}{
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+		{http.StatusNotFound, false},
+		{http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		err := &APIError{StatusCode: tt.statusCode}
+		if got := err.shouldRetry(); got != tt.want {
+			t.Errorf("shouldRetry() with status %d = %v, want %v", tt.statusCode, got, tt.want)
+		}
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"code":"UPSTREAM_TIMEOUT","msg":"upstream timed out"}`)),
+	}
+
+	err := newAPIError("/journeys", resp)
+
+	if err.StatusCode != http.StatusBadGateway {
+		t.Errorf("StatusCode = %d, want %d", err.StatusCode, http.StatusBadGateway)
+	}
+
+	if err.Endpoint != "/journeys" {
+		t.Errorf("Endpoint = %q, want %q", err.Endpoint, "/journeys")
+	}
+
+	if err.HafasCode != "UPSTREAM_TIMEOUT" {
+		t.Errorf("HafasCode = %q, want %q", err.HafasCode, "UPSTREAM_TIMEOUT")
+	}
+
+	if err.HafasMessage != "upstream timed out" {
+		t.Errorf("HafasMessage = %q, want %q", err.HafasMessage, "upstream timed out")
+	}
+}
+
+func TestNewAPIErrorWithoutBody(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}
+
+	err := newAPIError("/locations", resp)
+
+	if err.HafasCode != "" || err.HafasMessage != "" {
+		t.Errorf("expected no HAFAS fields parsed from an empty body, got %+v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package gtfs
+
+import "math"
+
+// gridPrecision is the geohash length used to bucket stops; at this precision each cell is
+// roughly gridCellMeters wide.
+const (
+	gridPrecision  = 6
+	gridCellMeters = 610.0
+	// maxGridRings caps how many rings of neighboring cells StopsWithin scans, so that a caller
+	// passing an unreasonably large radius cannot make the lookup scan an unbounded area.
+	maxGridRings = 20
+)
+
+const metersPerDegreeLat = 111320.0
+
+func metersPerDegreeLng(lat float64) float64 {
+	return metersPerDegreeLat * math.Cos(lat*math.Pi/180)
+}
+
+// geoGrid buckets stops into geohash cells of roughly fixed size, so that StopsWithin only has to
+// scan cells near the query point rather than every stop in the feed.
+type geoGrid struct {
+	cells map[string][]string // geohash -> stop IDs
+}
+
+func newGeoGrid() *geoGrid {
+	return &geoGrid{cells: make(map[string][]string)}
+}
+
+func (g *geoGrid) insert(s Stop) {
+	hash := geohashEncode(s.Latitude, s.Longitude, gridPrecision)
+	g.cells[hash] = append(g.cells[hash], s.ID)
+}
+
+// query returns the IDs of every stop in a cell that could contain a point within radiusMeters of
+// (lat, lng); callers still need to filter the result by exact distance.
+func (g *geoGrid) query(lat, lng float64, radiusMeters int) []string {
+	rings := int(float64(radiusMeters)/gridCellMeters) + 1
+	if rings > maxGridRings {
+		rings = maxGridRings
+	}
+
+	dLat := gridCellMeters / metersPerDegreeLat
+	dLng := gridCellMeters / metersPerDegreeLng(lat)
+
+	seen := make(map[string]bool)
+	var ids []string
+
+	for i := -rings; i <= rings; i++ {
+		for j := -rings; j <= rings; j++ {
+			hash := geohashEncode(lat+float64(i)*dLat, lng+float64(j)*dLng, gridPrecision)
+			if seen[hash] {
+				continue
+			}
+
+			seen[hash] = true
+			ids = append(ids, g.cells[hash]...)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,69 @@
+package gtfs
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeohashEncode(t *testing.T) {
+	// ezs42 is the canonical geohash.org reference value for this coordinate
+	if got := geohashEncode(42.6, -5.6, 5); got != "ezs42" {
+		t.Errorf("geohashEncode(42.6, -5.6, 5) = %q, want %q", got, "ezs42")
+	}
+
+	lat, lng := 52.520008, 13.404954
+	if got, want := geohashEncode(lat, lng, 7), geohashEncode(lat, lng, 5); got[:5] != want {
+		t.Errorf("geohashEncode(%v, %v, 7)[:5] = %q, want prefix %q", lat, lng, got[:5], want)
+	}
+}
+
+func TestHaversineMeters(t *testing.T) {
+	// One degree of latitude along a meridian is a quarter of the polar circumference / 90
+	want := earthRadiusMeters * math.Pi / 180
+	got := haversineMeters(0, 0, 1, 0)
+
+	if math.Abs(got-want) > 1 {
+		t.Errorf("haversineMeters(0, 0, 1, 0) = %v, want ~%v", got, want)
+	}
+
+	if got := haversineMeters(52.5, 13.4, 52.5, 13.4); got != 0 {
+		t.Errorf("haversineMeters of identical points = %v, want 0", got)
+	}
+}
+
+func TestFeedStopsWithin(t *testing.T) {
+	feed := &Feed{
+		stops: make(map[string]Stop),
+		grid:  newGeoGrid(),
+	}
+
+	stops := []Stop{
+		{ID: "near-1", Name: "Near Stop 1", Latitude: 52.520008, Longitude: 13.404954},
+		{ID: "near-2", Name: "Near Stop 2", Latitude: 52.520500, Longitude: 13.405500},
+		{ID: "far", Name: "Far Stop", Latitude: 52.6, Longitude: 13.5},
+	}
+
+	for _, s := range stops {
+		feed.stops[s.ID] = s
+		feed.grid.insert(s)
+	}
+
+	got := feed.StopsWithin(52.520008, 13.404954, 200)
+
+	ids := make(map[string]bool, len(got))
+	for _, s := range got {
+		ids[s.ID] = true
+	}
+
+	if !ids["near-1"] {
+		t.Errorf("StopsWithin: expected near-1 to be included, got %v", got)
+	}
+
+	if !ids["near-2"] {
+		t.Errorf("StopsWithin: expected near-2 to be included, got %v", got)
+	}
+
+	if ids["far"] {
+		t.Errorf("StopsWithin: expected far to be excluded, got %v", got)
+	}
+}
@@ -0,0 +1,67 @@
+package gtfs
+
+import (
+	"math"
+	"strings"
+)
+
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// geohashEncode returns the geohash of (lat, lng) truncated to precision characters
+func geohashEncode(lat, lng float64, precision int) string {
+	var (
+		latRange = [2]float64{-90, 90}
+		lngRange = [2]float64{-180, 180}
+		isEven   = true
+		bit      int
+		ch       int
+		hash     strings.Builder
+	)
+
+	for hash.Len() < precision {
+		if isEven {
+			mid := (lngRange[0] + lngRange[1]) / 2
+			if lng > mid {
+				ch |= 1 << uint(4-bit)
+				lngRange[0] = mid
+			} else {
+				lngRange[1] = mid
+			}
+		} else {
+			mid := (latRange[0] + latRange[1]) / 2
+			if lat > mid {
+				ch |= 1 << uint(4-bit)
+				latRange[0] = mid
+			} else {
+				latRange[1] = mid
+			}
+		}
+
+		isEven = !isEven
+
+		if bit < 4 {
+			bit++
+			continue
+		}
+
+		hash.WriteByte(geohashBase32[ch])
+		bit, ch = 0, 0
+	}
+
+	return hash.String()
+}
+
+const earthRadiusMeters = 6371000.0
+
+// haversineMeters returns the great-circle distance between two points in meters
+func haversineMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
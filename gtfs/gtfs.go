@@ -0,0 +1,461 @@
+// Package gtfs loads the VBB GTFS static feed into an in-memory index, so that stop lookups and
+// scheduled departures remain available without calling the HAFAS API.
+package gtfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stop is a GTFS stop or station
+type Stop struct {
+	ID        string
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+type route struct {
+	ID        string
+	ShortName string
+	LongName  string
+}
+
+type trip struct {
+	ID        string
+	RouteID   string
+	ServiceID string
+	Headsign  string
+}
+
+type stopTime struct {
+	TripID        string
+	StopID        string
+	ArrivalTime   time.Duration
+	DepartureTime time.Duration
+	StopSequence  int
+}
+
+type service struct {
+	Weekdays   [7]bool
+	Start, End time.Time
+	// Added and Removed are exceptions from calendar_dates.txt, keyed by YYYYMMDD date
+	Added, Removed map[string]bool
+}
+
+// Feed is an in-memory index of a GTFS static feed
+type Feed struct {
+	mu sync.RWMutex
+
+	stops     map[string]Stop
+	names     *trie
+	grid      *geoGrid
+	routes    map[string]route
+	trips     map[string]trip
+	stopTimes map[string][]stopTime // keyed by stop ID, sorted by departure time
+	calendar  map[string]service
+	version   string
+}
+
+// Load reads a GTFS feed from a local zip file
+func Load(path string) (*Feed, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GTFS feed %s: %w", path, err)
+	}
+
+	defer r.Close()
+
+	return newFeed(&r.Reader)
+}
+
+// LoadFromURL downloads and reads a GTFS feed zip archive
+func LoadFromURL(url string, c *http.Client) (*Feed, error) {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	resp, err := c.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download GTFS feed from %s: %w", url, err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GTFS feed from %s: %w", url, err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GTFS feed archive from %s: %w", url, err)
+	}
+
+	return newFeed(zr)
+}
+
+// LookupStopByID returns the stop with the given ID, if known
+func (f *Feed) LookupStopByID(id string) (Stop, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	s, ok := f.stops[id]
+
+	return s, ok
+}
+
+// SearchStopByName returns every stop whose name starts with prefix, case-insensitively
+func (f *Feed) SearchStopByName(prefix string) []Stop {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	ids := f.names.searchPrefix(prefix)
+	stops := make([]Stop, 0, len(ids))
+
+	for _, id := range ids {
+		if s, ok := f.stops[id]; ok {
+			stops = append(stops, s)
+		}
+	}
+
+	return stops
+}
+
+// StopsWithin returns every stop within radiusMeters great-circle distance of (lat, lng). Only
+// grid cells up to maxGridRings cells away are scanned, so stops farther than roughly
+// maxGridRings*gridCellMeters are not returned even when radiusMeters is larger.
+func (f *Feed) StopsWithin(lat, lng float64, radiusMeters int) []Stop {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var stops []Stop
+
+	for _, id := range f.grid.query(lat, lng, radiusMeters) {
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+
+		s, ok := f.stops[id]
+		if !ok {
+			continue
+		}
+
+		if haversineMeters(lat, lng, s.Latitude, s.Longitude) <= float64(radiusMeters) {
+			stops = append(stops, s)
+		}
+	}
+
+	return stops
+}
+
+// Version returns the feed_version of the loaded feed, as declared in feed_info.txt
+func (f *Feed) Version() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.version
+}
+
+func newFeed(zr *zip.Reader) (*Feed, error) {
+	feed := &Feed{
+		stops:     make(map[string]Stop),
+		names:     newTrie(),
+		grid:      newGeoGrid(),
+		routes:    make(map[string]route),
+		trips:     make(map[string]trip),
+		stopTimes: make(map[string][]stopTime),
+		calendar:  make(map[string]service),
+	}
+
+	if err := feed.loadStops(zr); err != nil {
+		return nil, err
+	}
+
+	if err := feed.loadRoutes(zr); err != nil {
+		return nil, err
+	}
+
+	if err := feed.loadTrips(zr); err != nil {
+		return nil, err
+	}
+
+	if err := feed.loadStopTimes(zr); err != nil {
+		return nil, err
+	}
+
+	if err := feed.loadCalendar(zr); err != nil {
+		return nil, err
+	}
+
+	if err := feed.loadCalendarDates(zr); err != nil {
+		return nil, err
+	}
+
+	feed.loadFeedInfo(zr)
+
+	return feed, nil
+}
+
+func (f *Feed) loadStops(zr *zip.Reader) error {
+	rows, err := readCSVFile(zr, "stops.txt")
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		lat, _ := strconv.ParseFloat(row["stop_lat"], 64)
+		lng, _ := strconv.ParseFloat(row["stop_lon"], 64)
+
+		s := Stop{
+			ID:        row["stop_id"],
+			Name:      row["stop_name"],
+			Latitude:  lat,
+			Longitude: lng,
+		}
+
+		f.stops[s.ID] = s
+		f.names.insert(s.Name, s.ID)
+		f.grid.insert(s)
+	}
+
+	return nil
+}
+
+func (f *Feed) loadRoutes(zr *zip.Reader) error {
+	rows, err := readCSVFile(zr, "routes.txt")
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		f.routes[row["route_id"]] = route{
+			ID:        row["route_id"],
+			ShortName: row["route_short_name"],
+			LongName:  row["route_long_name"],
+		}
+	}
+
+	return nil
+}
+
+func (f *Feed) loadTrips(zr *zip.Reader) error {
+	rows, err := readCSVFile(zr, "trips.txt")
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		f.trips[row["trip_id"]] = trip{
+			ID:        row["trip_id"],
+			RouteID:   row["route_id"],
+			ServiceID: row["service_id"],
+			Headsign:  row["trip_headsign"],
+		}
+	}
+
+	return nil
+}
+
+func (f *Feed) loadStopTimes(zr *zip.Reader) error {
+	rows, err := readCSVFile(zr, "stop_times.txt")
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		departure, err := parseGTFSTime(row["departure_time"])
+		if err != nil {
+			continue
+		}
+
+		arrival, err := parseGTFSTime(row["arrival_time"])
+		if err != nil {
+			arrival = departure
+		}
+
+		seq, _ := strconv.Atoi(row["stop_sequence"])
+
+		st := stopTime{
+			TripID:        row["trip_id"],
+			StopID:        row["stop_id"],
+			ArrivalTime:   arrival,
+			DepartureTime: departure,
+			StopSequence:  seq,
+		}
+
+		f.stopTimes[st.StopID] = append(f.stopTimes[st.StopID], st)
+	}
+
+	for _, times := range f.stopTimes {
+		sortStopTimes(times)
+	}
+
+	return nil
+}
+
+func (f *Feed) loadCalendar(zr *zip.Reader) error {
+	rows, err := readCSVFile(zr, "calendar.txt")
+	if err != nil {
+		// calendar.txt is optional if every service is defined via calendar_dates.txt
+		return nil
+	}
+
+	for _, row := range rows {
+		start, _ := time.Parse("20060102", row["start_date"])
+		end, _ := time.Parse("20060102", row["end_date"])
+
+		svc := service{
+			Start:   start,
+			End:     end,
+			Added:   make(map[string]bool),
+			Removed: make(map[string]bool),
+		}
+
+		svc.Weekdays[time.Sunday] = row["sunday"] == "1"
+		svc.Weekdays[time.Monday] = row["monday"] == "1"
+		svc.Weekdays[time.Tuesday] = row["tuesday"] == "1"
+		svc.Weekdays[time.Wednesday] = row["wednesday"] == "1"
+		svc.Weekdays[time.Thursday] = row["thursday"] == "1"
+		svc.Weekdays[time.Friday] = row["friday"] == "1"
+		svc.Weekdays[time.Saturday] = row["saturday"] == "1"
+
+		f.calendar[row["service_id"]] = svc
+	}
+
+	return nil
+}
+
+func (f *Feed) loadCalendarDates(zr *zip.Reader) error {
+	rows, err := readCSVFile(zr, "calendar_dates.txt")
+	if err != nil {
+		// calendar_dates.txt is optional
+		return nil
+	}
+
+	for _, row := range rows {
+		svc, ok := f.calendar[row["service_id"]]
+		if !ok {
+			svc = service{Added: make(map[string]bool), Removed: make(map[string]bool)}
+		}
+
+		switch row["exception_type"] {
+		case "1":
+			svc.Added[row["date"]] = true
+		case "2":
+			svc.Removed[row["date"]] = true
+		}
+
+		f.calendar[row["service_id"]] = svc
+	}
+
+	return nil
+}
+
+func (f *Feed) loadFeedInfo(zr *zip.Reader) {
+	rows, err := readCSVFile(zr, "feed_info.txt")
+	if err != nil || len(rows) == 0 {
+		return
+	}
+
+	f.version = rows[0]["feed_version"]
+}
+
+// findFile locates a file by its base name anywhere in the archive, since some publishers nest
+// the GTFS files inside a subdirectory.
+func findFile(zr *zip.Reader, name string) (*zip.File, error) {
+	for _, zf := range zr.File {
+		if zf.Name == name || strings.HasSuffix(zf.Name, "/"+name) {
+			return zf, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s not found in feed", name)
+}
+
+// readCSVFile reads a GTFS CSV file into a slice of column name -> value maps
+func readCSVFile(zr *zip.Reader, name string) ([]map[string]string, error) {
+	zf, err := findFile(zr, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := zf.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+
+	defer rc.Close()
+
+	r := csv.NewReader(rc)
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		row := make(map[string]string, len(header))
+		for i, col := range header {
+			if i < len(record) {
+				row[col] = strings.TrimSpace(record[i])
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseGTFSTime parses a GTFS HH:MM:SS time-of-day, where HH may exceed 24 for trips that run
+// past midnight, into a duration since midnight of the service day.
+func parseGTFSTime(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid GTFS time %q", s)
+	}
+
+	h, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid GTFS time %q: %w", s, err)
+	}
+
+	m, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid GTFS time %q: %w", s, err)
+	}
+
+	sec, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0, fmt.Errorf("invalid GTFS time %q: %w", s, err)
+	}
+
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second, nil
+}
@@ -0,0 +1,130 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+)
+
+// newTestFeed builds a zip archive in memory from the given GTFS files and loads it into a Feed
+func newTestFeed(t *testing.T, files map[string]string) *Feed {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to add %s to test fixture: %v", name, err)
+		}
+
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write %s to test fixture: %v", name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close test fixture archive: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to open test fixture archive: %v", err)
+	}
+
+	feed, err := newFeed(zr)
+	if err != nil {
+		t.Fatalf("newFeed() failed: %v", err)
+	}
+
+	return feed
+}
+
+const fixtureStops = "\ufeffstop_id,stop_name,stop_lat,stop_lon\n" +
+	"S1,Alexanderplatz,52.521918,13.413215\n"
+
+const fixtureRoutes = "route_id,route_short_name,route_long_name\n" +
+	"R1,M2,Alexanderplatz - Heinersdorf\n"
+
+const fixtureTrips = "trip_id,route_id,service_id,trip_headsign\n" +
+	"T-late,R1,daily,Heinersdorf\n" +
+	"T-early,R1,daily,Heinersdorf\n"
+
+const fixtureStopTimes = "trip_id,stop_id,arrival_time,departure_time,stop_sequence\n" +
+	"T-late,S1,23:55:00,23:55:00,1\n" +
+	"T-early,S1,00:05:00,00:05:00,1\n"
+
+const fixtureCalendar = "service_id,monday,tuesday,wednesday,thursday,friday,saturday,sunday,start_date,end_date\n" +
+	"daily,1,1,1,1,1,1,1,20240101,20241231\n"
+
+const fixtureFeedInfo = "feed_publisher_name,feed_publisher_url,feed_lang,feed_version\n" +
+	"VBB,https://vbb.de,de,2024-07\n"
+
+func testFixture() map[string]string {
+	return map[string]string{
+		"stops.txt":      fixtureStops,
+		"routes.txt":     fixtureRoutes,
+		"trips.txt":      fixtureTrips,
+		"stop_times.txt": fixtureStopTimes,
+		"calendar.txt":   fixtureCalendar,
+		"feed_info.txt":  fixtureFeedInfo,
+	}
+}
+
+func TestNewFeed(t *testing.T) {
+	feed := newTestFeed(t, testFixture())
+
+	s, ok := feed.LookupStopByID("S1")
+	if !ok {
+		t.Fatalf("LookupStopByID(%q) not found", "S1")
+	}
+
+	// The lookup above only finds S1 if the BOM was stripped from stops.txt's header, so that the
+	// first column is keyed stop_id rather than having the BOM stuck as a prefix on its name
+	if s.Name != "Alexanderplatz" {
+		t.Errorf("Stop.Name = %q, want %q", s.Name, "Alexanderplatz")
+	}
+
+	if feed.Version() != "2024-07" {
+		t.Errorf("Version() = %q, want %q", feed.Version(), "2024-07")
+	}
+
+	if len(feed.SearchStopByName("Alex")) != 1 {
+		t.Errorf("SearchStopByName(%q) = %v, want 1 result", "Alex", feed.SearchStopByName("Alex"))
+	}
+}
+
+func TestFeedScheduledDepartures(t *testing.T) {
+	feed := newTestFeed(t, testFixture())
+
+	loc := time.UTC
+	serviceDay := time.Date(2024, 7, 3, 23, 50, 0, 0, loc)
+
+	got, err := feed.ScheduledDepartures("S1", serviceDay, 20*time.Minute)
+	if err != nil {
+		t.Fatalf("ScheduledDepartures() returned unexpected error: %v", err)
+	}
+
+	trips := make(map[string]bool, len(got))
+	for _, d := range got {
+		trips[d.TripID] = true
+	}
+
+	if !trips["T-late"] {
+		t.Errorf("ScheduledDepartures() missing same-day departure at 23:55, got %v", got)
+	}
+
+	if !trips["T-early"] {
+		t.Errorf("ScheduledDepartures() missing next-day 00:05 departure within the window crossing midnight, got %v", got)
+	}
+}
+
+func TestFeedScheduledDeparturesUnknownStop(t *testing.T) {
+	feed := newTestFeed(t, testFixture())
+
+	if _, err := feed.ScheduledDepartures("does-not-exist", time.Now(), time.Hour); err == nil {
+		t.Fatalf("ScheduledDepartures() with an unknown stop succeeded, want error")
+	}
+}
@@ -0,0 +1,91 @@
+package gtfs
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ScheduledDeparture is a single scheduled departure produced by ScheduledDepartures
+type ScheduledDeparture struct {
+	TripID    string
+	RouteID   string
+	Headsign  string
+	Departure time.Time
+}
+
+func sortStopTimes(times []stopTime) {
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].DepartureTime < times[j].DepartureTime
+	})
+}
+
+// ScheduledDepartures returns the departures scheduled at stopID within [when, when+window),
+// computed from calendar.txt/calendar_dates.txt rather than live data.
+func (f *Feed) ScheduledDepartures(stopID string, when time.Time, window time.Duration) ([]ScheduledDeparture, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	times, ok := f.stopTimes[stopID]
+	if !ok {
+		return nil, fmt.Errorf("unknown stop %q", stopID)
+	}
+
+	end := when.Add(window)
+
+	var result []ScheduledDeparture
+
+	// A GTFS departure_time can exceed 24:00:00 for trips that started the previous service day, so
+	// a departure falling within the window may be keyed off the previous day; a window crossing
+	// midnight forward can likewise need the next day's ordinary, non-overflowing departures.
+	for _, dayOffset := range [...]int{-1, 0, 1} {
+		serviceDate := when.AddDate(0, 0, dayOffset)
+		midnight := time.Date(serviceDate.Year(), serviceDate.Month(), serviceDate.Day(), 0, 0, 0, 0, when.Location())
+
+		for _, st := range times {
+			trp, ok := f.trips[st.TripID]
+			if !ok || !f.serviceRunsOn(trp.ServiceID, serviceDate) {
+				continue
+			}
+
+			departure := midnight.Add(st.DepartureTime)
+			if departure.Before(when) || !departure.Before(end) {
+				continue
+			}
+
+			result = append(result, ScheduledDeparture{
+				TripID:    st.TripID,
+				RouteID:   trp.RouteID,
+				Headsign:  trp.Headsign,
+				Departure: departure,
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Departure.Before(result[j].Departure) })
+
+	return result, nil
+}
+
+func (f *Feed) serviceRunsOn(serviceID string, date time.Time) bool {
+	svc, ok := f.calendar[serviceID]
+	if !ok {
+		return false
+	}
+
+	dateKey := date.Format("20060102")
+
+	if svc.Removed[dateKey] {
+		return false
+	}
+
+	if svc.Added[dateKey] {
+		return true
+	}
+
+	if dateKey < svc.Start.Format("20060102") || dateKey > svc.End.Format("20060102") {
+		return false
+	}
+
+	return svc.Weekdays[date.Weekday()]
+}
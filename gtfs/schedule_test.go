@@ -0,0 +1,53 @@
+package gtfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServiceRunsOn(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	runningDate := time.Date(2024, 7, 3, 0, 0, 0, 0, time.UTC)
+	removedDate := runningDate.AddDate(0, 0, 7) // same weekday, a week later
+	outOfRangeDate := end.AddDate(0, 0, 1)
+
+	var weekdays [7]bool
+	weekdays[runningDate.Weekday()] = true
+
+	svc := service{
+		Start:    start,
+		End:      end,
+		Weekdays: weekdays,
+		Added:    map[string]bool{outOfRangeDate.Format("20060102"): true},
+		Removed:  map[string]bool{removedDate.Format("20060102"): true},
+	}
+
+	feed := &Feed{calendar: map[string]service{"svc": svc}}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"matches weekday within range", runningDate, true},
+		{"different weekday within range", runningDate.AddDate(0, 0, 1), false},
+		{"calendar_dates removed overrides matching weekday", removedDate, false},
+		{"calendar_dates added overrides out-of-range date", outOfRangeDate, true},
+		{"outside range with no exception", outOfRangeDate.AddDate(0, 0, 1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := feed.serviceRunsOn("svc", tt.date); got != tt.want {
+				t.Errorf("serviceRunsOn(%q, %s) = %v, want %v", "svc", tt.date.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown service", func(t *testing.T) {
+		if feed.serviceRunsOn("does-not-exist", runningDate) {
+			t.Errorf("serviceRunsOn of an unknown service = true, want false")
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package gtfs
+
+import "strings"
+
+// trieNode is a single character in a prefix tree of stop names
+type trieNode struct {
+	children map[rune]*trieNode
+	// stopIDs holds every stop whose (lowercased) name has the path to this node as a prefix
+	stopIDs []string
+}
+
+// trie indexes stop names for case-insensitive prefix search
+type trie struct {
+	root *trieNode
+}
+
+func newTrie() *trie {
+	return &trie{root: &trieNode{children: make(map[rune]*trieNode)}}
+}
+
+func (t *trie) insert(name, stopID string) {
+	node := t.root
+	node.stopIDs = append(node.stopIDs, stopID)
+
+	for _, r := range strings.ToLower(name) {
+		next, ok := node.children[r]
+		if !ok {
+			next = &trieNode{children: make(map[rune]*trieNode)}
+			node.children[r] = next
+		}
+
+		node = next
+		node.stopIDs = append(node.stopIDs, stopID)
+	}
+}
+
+// searchPrefix returns the IDs of every stop whose name starts with prefix, case-insensitively
+func (t *trie) searchPrefix(prefix string) []string {
+	node := t.root
+	for _, r := range strings.ToLower(prefix) {
+		next, ok := node.children[r]
+		if !ok {
+			return nil
+		}
+
+		node = next
+	}
+
+	return node.stopIDs
+}
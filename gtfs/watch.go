@@ -0,0 +1,82 @@
+package gtfs
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Watcher keeps a Feed up to date by periodically re-downloading it and swapping it in whenever
+// feed_info.txt's feed_version changes.
+type Watcher struct {
+	url string
+	c   *http.Client
+
+	mu   sync.RWMutex
+	feed *Feed
+
+	stop chan struct{}
+}
+
+// Watch downloads the feed at url and starts refreshing it every interval in the background
+func Watch(url string, c *http.Client, interval time.Duration) (*Watcher, error) {
+	feed, err := LoadFromURL(url, c)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		url:  url,
+		c:    c,
+		feed: feed,
+		stop: make(chan struct{}),
+	}
+
+	go w.run(interval)
+
+	return w, nil
+}
+
+// Feed returns the most recently loaded Feed
+func (w *Watcher) Feed() *Feed {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.feed
+}
+
+// Close stops the background refresh goroutine
+func (w *Watcher) Close() {
+	close(w.stop)
+}
+
+func (w *Watcher) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.refresh()
+		}
+	}
+}
+
+func (w *Watcher) refresh() {
+	feed, err := LoadFromURL(w.url, w.c)
+	if err != nil {
+		// Keep serving the last good feed rather than failing the watcher over a transient
+		// download error.
+		return
+	}
+
+	if feed.Version() == w.Feed().Version() {
+		return
+	}
+
+	w.mu.Lock()
+	w.feed = feed
+	w.mu.Unlock()
+}
@@ -0,0 +1,352 @@
+package vbb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Leg is a single ride or walk that is part of a Journey
+type Leg struct {
+	Origin, Destination Location
+	Departure           time.Time
+	PlannedDeparture    time.Time
+	Arrival             time.Time
+	PlannedArrival      time.Time
+	Delay               int
+	Line                Line
+	Direction           string
+	Walking             bool
+	Cancelled           bool
+	// Polyline is the decoded leg geometry, populated when JourneyOptions.Polylines is set
+	Polyline []Location
+}
+
+// Journey is a trip from one Location to another composed of one or more Legs
+type Journey struct {
+	Legs []Leg
+	// Remarks carries service alerts, e.g. disruptions or cancellations, attached to the journey
+	Remarks []Remark
+}
+
+// JourneysResult is the response of a trip search, including pagination refs for JourneysEarlier/JourneysLater
+type JourneysResult struct {
+	Journeys []Journey
+	// EarlierRef and LaterRef are HAFAS context tokens for fetching the previous/next page of results
+	EarlierRef string
+	LaterRef   string
+}
+
+// Accessibility represents the level of accessibility required from a journey
+type Accessibility uint8
+
+const (
+	AccessibilityNone Accessibility = iota
+	AccessibilityPartial
+	AccessibilityComplete
+)
+
+// String returns the HAFAS@v6 accessibility query value
+func (a Accessibility) String() string {
+	switch a {
+	case AccessibilityPartial:
+		return "partial"
+	case AccessibilityComplete:
+		return "complete"
+	default:
+		return "none"
+	}
+}
+
+// JourneyOptions controls trip search parameters
+type JourneyOptions struct {
+	// Departure and Arrival are mutually exclusive; leave both zero to search from now
+	Departure, Arrival time.Time
+
+	Results int
+	// Transfers is the maximum number of transfers, -1 for unlimited; defaults to 0, i.e. direct connections only
+	Transfers int
+	// TransferTime is the minimum time to reserve for each transfer
+	TransferTime time.Duration
+
+	Stopovers     bool
+	Accessibility Accessibility
+	BikeFriendly  bool
+	Tickets       bool
+	Polylines     bool
+
+	TransportationType TransportationType
+}
+
+// queryValues renders opts as HAFAS@v6 query parameters, shared by Journeys, JourneysEarlier and JourneysLater
+func (opts JourneyOptions) queryValues() url.Values {
+	q := addTransportTypeParams(make(url.Values), opts.TransportationType)
+
+	if !opts.Departure.IsZero() {
+		q.Set("departure", opts.Departure.Format("2006-01-02T15:04:05-0700"))
+	}
+
+	if !opts.Arrival.IsZero() {
+		q.Set("arrival", opts.Arrival.Format("2006-01-02T15:04:05-0700"))
+	}
+
+	if opts.Results > 0 {
+		q.Set("results", strconv.Itoa(opts.Results))
+	}
+
+	q.Set("transfers", strconv.Itoa(opts.Transfers))
+
+	if opts.TransferTime > 0 {
+		q.Set("transferTime", strconv.Itoa(int(opts.TransferTime.Minutes())))
+	}
+
+	q.Set("stopovers", strconv.FormatBool(opts.Stopovers))
+	q.Set("accessibility", opts.Accessibility.String())
+	q.Set("bikeFriendly", strconv.FormatBool(opts.BikeFriendly))
+	q.Set("tickets", strconv.FormatBool(opts.Tickets))
+	q.Set("polylines", strconv.FormatBool(opts.Polylines))
+	q.Set("pretty", "false")
+
+	return q
+}
+
+// setLocationParam renders loc as the from/to/via query parameter HAFAS@v6 expects:
+// a stop ID for stops, or coordinates/address for ad-hoc locations
+func setLocationParam(q url.Values, param string, loc Location) {
+	if loc.Type == "stop" && loc.ID != "" {
+		q.Set(param, loc.ID)
+		return
+	}
+
+	q.Set(param+".latitude", strconv.FormatFloat(loc.Latitude, 'f', -1, 64))
+	q.Set(param+".longitude", strconv.FormatFloat(loc.Longitude, 'f', -1, 64))
+
+	if loc.Address != "" {
+		q.Set(param+".address", loc.Address)
+	}
+}
+
+type hafasPolylineFeature struct {
+	Geometry struct {
+		Type        string
+		Coordinates json.RawMessage
+	}
+}
+
+type hafasPolyline struct {
+	Features []hafasPolylineFeature
+}
+
+// decode concatenates the decoded coordinates of every LineString feature into a single slice of Location
+func (p *hafasPolyline) decode() ([]Location, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	var locs []Location
+	for _, f := range p.Features {
+		if f.Geometry.Type != "LineString" {
+			continue
+		}
+
+		var encoded string
+		if err := json.Unmarshal(f.Geometry.Coordinates, &encoded); err != nil {
+			continue
+		}
+
+		decoded, err := decodePolyline(encoded)
+		if err != nil {
+			return nil, err
+		}
+
+		locs = append(locs, decoded...)
+	}
+
+	return locs, nil
+}
+
+// decodePolyline decodes a Google encoded polyline string into a slice of Location
+func decodePolyline(encoded string) ([]Location, error) {
+	var (
+		index, lat, lng int
+		locs            []Location
+	)
+
+	for index < len(encoded) {
+		dLat, err := decodePolylineValue(encoded, &index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode polyline: %w", err)
+		}
+
+		dLng, err := decodePolylineValue(encoded, &index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode polyline: %w", err)
+		}
+
+		lat += dLat
+		lng += dLng
+
+		locs = append(locs, Location{
+			Type:      "location",
+			Latitude:  float64(lat) / 1e5,
+			Longitude: float64(lng) / 1e5,
+		})
+	}
+
+	return locs, nil
+}
+
+// decodePolylineValue decodes a single varint-encoded, delta-compressed coordinate starting at
+// *index, returning an error if encoded is truncated mid-coordinate.
+func decodePolylineValue(encoded string, index *int) (int, error) {
+	var (
+		result int
+		shift  uint
+		b      int
+	)
+
+	for {
+		if *index >= len(encoded) {
+			return 0, fmt.Errorf("truncated polyline at offset %d", *index)
+		}
+
+		b = int(encoded[*index]) - 63
+		*index++
+		result |= (b & 0x1f) << shift
+		shift += 5
+
+		if b < 0x20 {
+			break
+		}
+	}
+
+	if result&1 != 0 {
+		return ^(result >> 1), nil
+	}
+
+	return result >> 1, nil
+}
+
+type hafasLeg struct {
+	Origin           Location
+	Destination      Location
+	Departure        time.Time
+	PlannedDeparture time.Time
+	Arrival          time.Time
+	PlannedArrival   time.Time
+	Delay            int
+	Line             Line
+	Direction        string
+	Walking          bool
+	Cancelled        bool
+	Polyline         *hafasPolyline
+}
+
+func (hl hafasLeg) toLeg() (Leg, error) {
+	polyline, err := hl.Polyline.decode()
+	if err != nil {
+		return Leg{}, err
+	}
+
+	return Leg{
+		Origin:           hl.Origin,
+		Destination:      hl.Destination,
+		Departure:        hl.Departure,
+		PlannedDeparture: hl.PlannedDeparture,
+		Arrival:          hl.Arrival,
+		PlannedArrival:   hl.PlannedArrival,
+		Delay:            hl.Delay,
+		Line:             hl.Line,
+		Direction:        hl.Direction,
+		Walking:          hl.Walking,
+		Cancelled:        hl.Cancelled,
+		Polyline:         polyline,
+	}, nil
+}
+
+type hafasJourney struct {
+	Legs    []hafasLeg
+	Remarks []Remark
+}
+
+func (hj hafasJourney) toJourney() (Journey, error) {
+	legs := make([]Leg, 0, len(hj.Legs))
+	for _, l := range hj.Legs {
+		leg, err := l.toLeg()
+		if err != nil {
+			return Journey{}, err
+		}
+
+		legs = append(legs, leg)
+	}
+
+	return Journey{
+		Legs:    legs,
+		Remarks: hj.Remarks,
+	}, nil
+}
+
+type hafasJourneysResponse struct {
+	Journeys   []hafasJourney
+	EarlierRef string
+	LaterRef   string
+}
+
+// journeys sends a request to /journeys with the given query and decodes the response
+func (c *Client) journeys(q url.Values) (JourneysResult, error) {
+	data, err := c.sendRequest(http.MethodGet, "/journeys?"+q.Encode())
+	if err != nil {
+		return JourneysResult{}, fmt.Errorf("failed to retrieve journeys: %w", err)
+	}
+
+	defer data.Close()
+
+	var res hafasJourneysResponse
+	if err := json.NewDecoder(data).Decode(&res); err != nil {
+		return JourneysResult{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	journeys := make([]Journey, 0, len(res.Journeys))
+	for _, j := range res.Journeys {
+		journey, err := j.toJourney()
+		if err != nil {
+			return JourneysResult{}, fmt.Errorf("failed to decode journey: %w", err)
+		}
+
+		journeys = append(journeys, journey)
+	}
+
+	return JourneysResult{
+		Journeys:   journeys,
+		EarlierRef: res.EarlierRef,
+		LaterRef:   res.LaterRef,
+	}, nil
+}
+
+// Journeys returns a list of trips from one Location to another matching opts
+func (c *Client) Journeys(from, to Location, opts JourneyOptions) (JourneysResult, error) {
+	q := opts.queryValues()
+	setLocationParam(q, "from", from)
+	setLocationParam(q, "to", to)
+
+	return c.journeys(q)
+}
+
+// JourneysEarlier returns the page of journeys preceding ref, as returned in JourneysResult.EarlierRef
+func (c *Client) JourneysEarlier(ref string, opts JourneyOptions) (JourneysResult, error) {
+	q := opts.queryValues()
+	q.Set("earlierThan", ref)
+
+	return c.journeys(q)
+}
+
+// JourneysLater returns the page of journeys following ref, as returned in JourneysResult.LaterRef
+func (c *Client) JourneysLater(ref string, opts JourneyOptions) (JourneysResult, error) {
+	q := opts.queryValues()
+	q.Set("laterThan", ref)
+
+	return c.journeys(q)
+}
@@ -0,0 +1,79 @@
+package vbb
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecodePolyline(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+		want    []Location
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			encoded: "",
+			want:    nil,
+		},
+		{
+			name:    "well-known Google example",
+			encoded: "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+			want: []Location{
+				{Type: "location", Latitude: 38.5, Longitude: -120.2},
+				{Type: "location", Latitude: 40.7, Longitude: -120.95},
+				{Type: "location", Latitude: 43.252, Longitude: -126.453},
+			},
+		},
+		{
+			name:    "truncated mid-coordinate",
+			encoded: "_p~iF",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodePolyline(tt.encoded)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodePolyline(%q) succeeded unexpectedly, got %v", tt.encoded, got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("decodePolyline(%q) returned unexpected error: %v", tt.encoded, err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("decodePolyline(%q) = %v, want %v", tt.encoded, got, tt.want)
+			}
+
+			const epsilon = 1e-6
+
+			for i, loc := range got {
+				if loc.Type != tt.want[i].Type ||
+					math.Abs(loc.Latitude-tt.want[i].Latitude) > epsilon ||
+					math.Abs(loc.Longitude-tt.want[i].Longitude) > epsilon {
+					t.Errorf("decodePolyline(%q)[%d] = %+v, want %+v", tt.encoded, i, loc, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHafasPolylineDecodeNil(t *testing.T) {
+	var p *hafasPolyline
+
+	locs, err := p.decode()
+	if err != nil {
+		t.Fatalf("nil *hafasPolyline.decode() returned unexpected error: %v", err)
+	}
+
+	if locs != nil {
+		t.Fatalf("nil *hafasPolyline.decode() = %v, want nil", locs)
+	}
+}
@@ -0,0 +1,249 @@
+package vbb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// BoundingBox is a rectangular geographic area used to query vehicles currently within it
+type BoundingBox struct {
+	North, West, South, East float64
+}
+
+// StopoverPrediction is a predicted arrival/departure at a stop along a Movement or Trip
+type StopoverPrediction struct {
+	Stop             Location
+	Arrival          time.Time
+	PlannedArrival   time.Time
+	Departure        time.Time
+	PlannedDeparture time.Time
+	Platform         string
+	PlannedPlatform  string
+}
+
+// Frame is a segment of a Movement between two consecutive stops
+type Frame struct {
+	Origin, Destination Location
+	Departure           time.Time
+	PlannedDeparture    time.Time
+	Arrival             time.Time
+	PlannedArrival      time.Time
+	Polyline            []Location
+}
+
+// Movement describes a vehicle currently running within a BoundingBox
+type Movement struct {
+	TripID    string
+	Line      Line
+	Direction string
+	Location  Location
+	NextStops []StopoverPrediction
+	Frames    []Frame
+}
+
+// RadarOptions controls Radar query parameters
+type RadarOptions struct {
+	Results   int
+	Duration  time.Duration
+	Polylines bool
+
+	TransportationType TransportationType
+}
+
+// Radar returns the vehicles currently located within bbox
+func (c *Client) Radar(bbox BoundingBox, opts RadarOptions) ([]Movement, error) {
+	q := addTransportTypeParams(make(url.Values), opts.TransportationType)
+
+	q.Set("north", strconv.FormatFloat(bbox.North, 'f', -1, 64))
+	q.Set("west", strconv.FormatFloat(bbox.West, 'f', -1, 64))
+	q.Set("south", strconv.FormatFloat(bbox.South, 'f', -1, 64))
+	q.Set("east", strconv.FormatFloat(bbox.East, 'f', -1, 64))
+
+	if opts.Results > 0 {
+		q.Set("results", strconv.Itoa(opts.Results))
+	}
+
+	if opts.Duration > 0 {
+		q.Set("duration", strconv.Itoa(int(opts.Duration.Seconds())))
+	}
+
+	q.Set("polylines", strconv.FormatBool(opts.Polylines))
+	q.Set("pretty", "false")
+
+	data, err := c.sendRequest(http.MethodGet, "/radar?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve radar movements: %w", err)
+	}
+
+	defer data.Close()
+
+	var res []hafasMovement
+	if err := json.NewDecoder(data).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	movements := make([]Movement, 0, len(res))
+	for _, m := range res {
+		movement, err := m.toMovement()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode movement: %w", err)
+		}
+
+		movements = append(movements, movement)
+	}
+
+	return movements, nil
+}
+
+// TripOptions controls which extra data Trip includes in its response
+type TripOptions struct {
+	Stopovers bool
+	Polyline  bool
+}
+
+// Trip is the full stopover list and current predicted position of a single vehicle run
+type Trip struct {
+	ID              string
+	Line            Line
+	Direction       string
+	Stopovers       []StopoverPrediction
+	Polyline        []Location
+	CurrentLocation Location
+}
+
+// Trip returns the full stopover list and current predicted position for the run identified by
+// tripID, as found in Departure.TripID
+func (c *Client) Trip(tripID string, opts TripOptions) (Trip, error) {
+	q := make(url.Values)
+	q.Set("stopovers", strconv.FormatBool(opts.Stopovers))
+	q.Set("polyline", strconv.FormatBool(opts.Polyline))
+	q.Set("pretty", "false")
+
+	data, err := c.sendRequest(http.MethodGet, "/trips/"+url.PathEscape(tripID)+"?"+q.Encode())
+	if err != nil {
+		return Trip{}, fmt.Errorf("failed to retrieve trip %s: %w", tripID, err)
+	}
+
+	defer data.Close()
+
+	var res hafasTrip
+	if err := json.NewDecoder(data).Decode(&res); err != nil {
+		return Trip{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	trip, err := res.toTrip()
+	if err != nil {
+		return Trip{}, fmt.Errorf("failed to decode trip: %w", err)
+	}
+
+	return trip, nil
+}
+
+type hafasStopoverPrediction struct {
+	Stop             Location
+	Arrival          time.Time
+	PlannedArrival   time.Time
+	Departure        time.Time
+	PlannedDeparture time.Time
+	Platform         string
+	PlannedPlatform  string
+}
+
+func (hsp hafasStopoverPrediction) toStopoverPrediction() StopoverPrediction {
+	return StopoverPrediction(hsp)
+}
+
+type hafasFrame struct {
+	Origin, Destination Location
+	Departure           time.Time
+	PlannedDeparture    time.Time
+	Arrival             time.Time
+	PlannedArrival      time.Time
+	Polyline            *hafasPolyline
+}
+
+func (hf hafasFrame) toFrame() (Frame, error) {
+	polyline, err := hf.Polyline.decode()
+	if err != nil {
+		return Frame{}, err
+	}
+
+	return Frame{
+		Origin:           hf.Origin,
+		Destination:      hf.Destination,
+		Departure:        hf.Departure,
+		PlannedDeparture: hf.PlannedDeparture,
+		Arrival:          hf.Arrival,
+		PlannedArrival:   hf.PlannedArrival,
+		Polyline:         polyline,
+	}, nil
+}
+
+type hafasMovement struct {
+	TripID    string
+	Line      Line
+	Direction string
+	Location  Location
+	NextStops []hafasStopoverPrediction
+	Frames    []hafasFrame
+}
+
+func (hm hafasMovement) toMovement() (Movement, error) {
+	nextStops := make([]StopoverPrediction, 0, len(hm.NextStops))
+	for _, s := range hm.NextStops {
+		nextStops = append(nextStops, s.toStopoverPrediction())
+	}
+
+	frames := make([]Frame, 0, len(hm.Frames))
+	for _, f := range hm.Frames {
+		frame, err := f.toFrame()
+		if err != nil {
+			return Movement{}, err
+		}
+
+		frames = append(frames, frame)
+	}
+
+	return Movement{
+		TripID:    hm.TripID,
+		Line:      hm.Line,
+		Direction: hm.Direction,
+		Location:  hm.Location,
+		NextStops: nextStops,
+		Frames:    frames,
+	}, nil
+}
+
+type hafasTrip struct {
+	ID              string
+	Line            Line
+	Direction       string
+	Stopovers       []hafasStopoverPrediction
+	Polyline        *hafasPolyline
+	CurrentLocation Location
+}
+
+func (ht hafasTrip) toTrip() (Trip, error) {
+	stopovers := make([]StopoverPrediction, 0, len(ht.Stopovers))
+	for _, s := range ht.Stopovers {
+		stopovers = append(stopovers, s.toStopoverPrediction())
+	}
+
+	polyline, err := ht.Polyline.decode()
+	if err != nil {
+		return Trip{}, err
+	}
+
+	return Trip{
+		ID:              ht.ID,
+		Line:            ht.Line,
+		Direction:       ht.Direction,
+		Stopovers:       stopovers,
+		Polyline:        polyline,
+		CurrentLocation: ht.CurrentLocation,
+	}, nil
+}
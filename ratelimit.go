@@ -0,0 +1,106 @@
+package vbb
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a simple token bucket throttling requests to an average rate, while allowing
+// short bursts up to its capacity.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	ratePerSecond float64
+	burst         float64
+	tokens        float64
+	last          time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing requestsPerMinute on average, with bursts of up
+// to burst requests.
+func NewRateLimiter(requestsPerMinute, burst int) *RateLimiter {
+	return &RateLimiter{
+		ratePerSecond: float64(requestsPerMinute) / 60,
+		burst:         float64(burst),
+		tokens:        float64(burst),
+		last:          time.Now(),
+	}
+}
+
+// Wait blocks until a token is available, then consumes it
+func (r *RateLimiter) Wait() {
+	for {
+		d := r.reserve()
+		if d <= 0 {
+			return
+		}
+
+		time.Sleep(d)
+	}
+}
+
+// reserve refills the bucket and either consumes a token (returning 0) or returns how long the
+// caller should sleep before trying again.
+func (r *RateLimiter) reserve() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Seconds() * r.ratePerSecond
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+
+	r.last = now
+
+	if r.tokens >= 1 {
+		r.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - r.tokens) / r.ratePerSecond * float64(time.Second))
+}
+
+// RetryPolicy controls how Client.sendRequest retries failed requests. Only responses with
+// status 429 or >= 500 are retried, honoring the Retry-After header when present.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      bool
+}
+
+// backoff returns how long to wait before retry number attempt (1-based)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << uint(attempt-1)
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter && d > 0 {
+		d = time.Duration(rand.Int63n(int64(d)))
+	}
+
+	return d
+}
+
+// retryDelay returns how long to wait before retrying a request that got resp, honoring
+// Retry-After when the server sent one.
+func (p RetryPolicy) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+
+		if t, err := http.ParseTime(ra); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d
+			}
+		}
+	}
+
+	return p.backoff(attempt)
+}
@@ -0,0 +1,83 @@
+package vbb
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	rl := NewRateLimiter(60, 2) // 1 token/sec, burst of 2
+
+	if d := rl.reserve(); d != 0 {
+		t.Fatalf("reserve() on a fresh bucket = %v, want 0", d)
+	}
+
+	if d := rl.reserve(); d != 0 {
+		t.Fatalf("reserve() for the second burst token = %v, want 0", d)
+	}
+
+	if d := rl.reserve(); d <= 0 {
+		t.Fatalf("reserve() with an exhausted bucket = %v, want > 0", d)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second}, // uncapped would be 1.6s, MaxDelay caps it
+	}
+
+	for _, tt := range tests {
+		if got := p.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, Jitter: true}
+	max := p.BaseDelay << 2 // attempt 3
+
+	for i := 0; i < 20; i++ {
+		if got := p.backoff(3); got < 0 || got >= max {
+			t.Fatalf("backoff(3) = %v, want within [0, %v)", got, max)
+		}
+	}
+}
+
+func TestRetryPolicyRetryDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 50 * time.Millisecond}
+
+	t.Run("numeric Retry-After", func(t *testing.T) {
+		resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+		if got := p.retryDelay(resp, 1); got != 2*time.Second {
+			t.Errorf("retryDelay() = %v, want 2s", got)
+		}
+	})
+
+	t.Run("HTTP-date Retry-After", func(t *testing.T) {
+		future := time.Now().Add(3 * time.Second)
+		resp := &http.Response{Header: http.Header{"Retry-After": []string{future.UTC().Format(http.TimeFormat)}}}
+
+		got := p.retryDelay(resp, 1)
+		if got <= 0 || got > 4*time.Second {
+			t.Errorf("retryDelay() = %v, want ~3s", got)
+		}
+	})
+
+	t.Run("falls back to backoff without Retry-After", func(t *testing.T) {
+		resp := &http.Response{Header: http.Header{}}
+		if got := p.retryDelay(resp, 2); got != 100*time.Millisecond {
+			t.Errorf("retryDelay() = %v, want 100ms", got)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package vbb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Remark is a service alert attached to a Journey, e.g. a disruption or cancellation notice
+type Remark struct {
+	Type    string
+	Code    string
+	Summary string
+	Text    string
+}
+
+// RefreshOptions controls which extra data RefreshJourney includes in its response
+type RefreshOptions struct {
+	Stopovers bool
+	Tickets   bool
+	Polylines bool
+	Remarks   bool
+	// Language is the IETF language tag remarks are translated to, e.g. "de"
+	Language string
+}
+
+func (opts RefreshOptions) queryValues() url.Values {
+	q := make(url.Values)
+
+	q.Set("stopovers", strconv.FormatBool(opts.Stopovers))
+	q.Set("tickets", strconv.FormatBool(opts.Tickets))
+	q.Set("polylines", strconv.FormatBool(opts.Polylines))
+	q.Set("remarks", strconv.FormatBool(opts.Remarks))
+	q.Set("pretty", "false")
+
+	if opts.Language != "" {
+		q.Set("language", opts.Language)
+	}
+
+	return q
+}
+
+// RefreshJourney re-fetches a previously planned Journey by its refresh token, returning up to date
+// delays and cancellations without re-running trip search. Leg has no platform field, so platform
+// changes are not reported.
+func (c *Client) RefreshJourney(refreshToken string, opts RefreshOptions) (Journey, error) {
+	q := opts.queryValues()
+
+	data, err := c.sendRequest(http.MethodGet, "/journeys/"+url.PathEscape(refreshToken)+"?"+q.Encode())
+	if err != nil {
+		return Journey{}, fmt.Errorf("failed to refresh journey %s: %w", refreshToken, err)
+	}
+
+	defer data.Close()
+
+	var res struct {
+		Journey hafasJourney
+	}
+	if err := json.NewDecoder(data).Decode(&res); err != nil {
+		return Journey{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	journey, err := res.Journey.toJourney()
+	if err != nil {
+		return Journey{}, fmt.Errorf("failed to decode journey: %w", err)
+	}
+
+	return journey, nil
+}
@@ -1,13 +1,17 @@
 package vbb
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/andrewslotin/vbb/gtfs"
 )
 
 const BaseURL = "https://v6.vbb.transport.rest"
@@ -16,6 +20,51 @@ const BaseURL = "https://v6.vbb.transport.rest"
 type Client struct {
 	endpoint string
 	c        *http.Client
+	cache    Cache
+
+	// LocationsTTL is how long Locations responses are cached for
+	LocationsTTL time.Duration
+	// DeparturesTTL is how long Departures and Arrivals responses are cached for
+	DeparturesTTL time.Duration
+	// JourneysTTL is how long Journeys and RefreshJourney responses are cached for
+	JourneysTTL time.Duration
+	// StopsNearbyTTL is how long StopsNearby responses are cached for
+	StopsNearbyTTL time.Duration
+
+	// Offline is a GTFS feed index used as a fallback data source for Locations and StopsNearby
+	// when the HAFAS endpoint returns an error, or always when OfflineFirst is set. Offline itself
+	// is not synchronized, so it must not be reassigned while requests may be in flight; use
+	// OfflineWatcher instead if the feed needs to be kept up to date automatically.
+	Offline *gtfs.Feed
+	// OfflineWatcher, if set, is consulted instead of Offline and stays up to date in the
+	// background via gtfs.Watch, making it safe to keep serving requests while it refreshes.
+	OfflineWatcher *gtfs.Watcher
+	// OfflineFirst makes Locations (when stops are requested) and StopsNearby answer from the
+	// offline feed alone, without calling HAFAS
+	OfflineFirst bool
+
+	// RateLimit throttles outgoing requests; defaults to v6.vbb.transport.rest's advertised limit
+	// of 100 requests per minute. Set to nil to disable throttling.
+	RateLimit *RateLimiter
+	// Retry controls how failed requests are retried; see RetryPolicy.
+	Retry RetryPolicy
+}
+
+const (
+	defaultLocationsTTL   = 7 * 24 * time.Hour
+	defaultDeparturesTTL  = time.Minute
+	defaultJourneysTTL    = 30 * time.Second
+	defaultStopsNearbyTTL = 24 * time.Hour
+
+	// defaultRateLimit matches v6.vbb.transport.rest's advertised limit
+	defaultRateLimit = 100 // requests per minute
+)
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Jitter:      true,
 }
 
 // New returns a new instance of VBB API client
@@ -27,9 +76,26 @@ func New(endpoint string, c *http.Client) *Client {
 	return &Client{
 		endpoint: endpoint,
 		c:        c,
+
+		LocationsTTL:   defaultLocationsTTL,
+		DeparturesTTL:  defaultDeparturesTTL,
+		JourneysTTL:    defaultJourneysTTL,
+		StopsNearbyTTL: defaultStopsNearbyTTL,
+
+		RateLimit: NewRateLimiter(defaultRateLimit, defaultRateLimit),
+		Retry:     defaultRetryPolicy,
 	}
 }
 
+// NewWithCache returns a new instance of VBB API client that caches responses in cache according to
+// Client.LocationsTTL, Client.DeparturesTTL, Client.JourneysTTL and Client.StopsNearbyTTL
+func NewWithCache(endpoint string, httpClient *http.Client, cache Cache) *Client {
+	client := New(endpoint, httpClient)
+	client.cache = cache
+
+	return client
+}
+
 // Location is a station, stop, POI or an address
 type Location struct {
 	Type                string
@@ -113,8 +179,22 @@ const (
 	LocationTypeAny = LocationTypeStop | LocationTypeAddress | LocationTypePOI
 )
 
+// offlineFeed returns the GTFS feed to consult for offline fallback lookups, preferring
+// OfflineWatcher's continuously refreshed feed over the static Offline field
+func (c *Client) offlineFeed() *gtfs.Feed {
+	if c.OfflineWatcher != nil {
+		return c.OfflineWatcher.Feed()
+	}
+
+	return c.Offline
+}
+
 // Locations returns first resultsNum locations matching the query
 func (c *Client) Locations(query string, locType LocationType, resultsNum int) ([]Location, error) {
+	if feed := c.offlineFeed(); feed != nil && c.OfflineFirst && locType&LocationTypeStop != 0 {
+		return locationsFromOffline(feed, query, resultsNum), nil
+	}
+
 	q := make(url.Values)
 	q.Set("results", strconv.Itoa(resultsNum))
 	q.Set("query", query)
@@ -127,6 +207,10 @@ func (c *Client) Locations(query string, locType LocationType, resultsNum int) (
 
 	data, err := c.sendRequest(http.MethodGet, "/locations?"+q.Encode())
 	if err != nil {
+		if feed := c.offlineFeed(); feed != nil && locType&LocationTypeStop != 0 {
+			return locationsFromOffline(feed, query, resultsNum), nil
+		}
+
 		return nil, fmt.Errorf("failed to retrieve locations: %w", err)
 	}
 
@@ -140,8 +224,35 @@ func (c *Client) Locations(query string, locType LocationType, resultsNum int) (
 	return results, nil
 }
 
+// locationsFromOffline serves a stop name search from feed when the HAFAS endpoint is
+// unavailable or OfflineFirst is set. It can only ever return stops, since the static GTFS feed
+// has no notion of addresses or POIs.
+func locationsFromOffline(feed *gtfs.Feed, query string, resultsNum int) []Location {
+	stops := feed.SearchStopByName(query)
+	if resultsNum > 0 && len(stops) > resultsNum {
+		stops = stops[:resultsNum]
+	}
+
+	locations := make([]Location, 0, len(stops))
+	for _, s := range stops {
+		locations = append(locations, Location{
+			Type:      "stop",
+			ID:        s.ID,
+			Name:      s.Name,
+			Latitude:  s.Latitude,
+			Longitude: s.Longitude,
+		})
+	}
+
+	return locations
+}
+
 // StopsNearby returns resultsNum stops within distance meters of walking from given location
 func (c *Client) StopsNearby(lat, lng float64, distance, resultsNum int) ([]Location, error) {
+	if feed := c.offlineFeed(); feed != nil && c.OfflineFirst {
+		return stopsNearbyFromOffline(feed, lat, lng, distance, resultsNum), nil
+	}
+
 	q := make(url.Values)
 	q.Set("results", strconv.Itoa(resultsNum))
 	q.Set("latitude", strconv.FormatFloat(lat, 'f', -1, 64))
@@ -151,6 +262,10 @@ func (c *Client) StopsNearby(lat, lng float64, distance, resultsNum int) ([]Loca
 
 	data, err := c.sendRequest(http.MethodGet, "/locations/nearby?"+q.Encode())
 	if err != nil {
+		if feed := c.offlineFeed(); feed != nil {
+			return stopsNearbyFromOffline(feed, lat, lng, distance, resultsNum), nil
+		}
+
 		return nil, fmt.Errorf("failed to retrieve stops nearby: %w", err)
 	}
 
@@ -164,6 +279,28 @@ func (c *Client) StopsNearby(lat, lng float64, distance, resultsNum int) ([]Loca
 	return results, nil
 }
 
+// stopsNearbyFromOffline serves StopsNearby from feed when the HAFAS endpoint is unavailable
+// or OfflineFirst is set
+func stopsNearbyFromOffline(feed *gtfs.Feed, lat, lng float64, distance, resultsNum int) []Location {
+	stops := feed.StopsWithin(lat, lng, distance)
+	if resultsNum > 0 && len(stops) > resultsNum {
+		stops = stops[:resultsNum]
+	}
+
+	locations := make([]Location, 0, len(stops))
+	for _, s := range stops {
+		locations = append(locations, Location{
+			Type:      "stop",
+			ID:        s.ID,
+			Name:      s.Name,
+			Latitude:  s.Latitude,
+			Longitude: s.Longitude,
+		})
+	}
+
+	return locations
+}
+
 // TransportationType represents the type transport type
 type TransportationType uint8
 
@@ -188,6 +325,7 @@ type Line struct {
 
 // Departure represents departure information
 type Departure struct {
+	TripID          string
 	Direction       string
 	When            time.Time
 	PlannedWhen     time.Time
@@ -271,15 +409,87 @@ func addTransportTypeParams(q url.Values, transportTypes TransportationType) url
 }
 
 func (c *Client) sendRequest(method, url string) (io.ReadCloser, error) {
-	req, err := http.NewRequest(method, c.endpoint+url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request to %s: %w", url, err)
+	ttl := c.cacheTTL(url)
+	key := method + " " + url
+
+	if c.cache != nil && ttl > 0 {
+		if body, ok := c.cache.Get(key); ok {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	maxAttempts := c.Retry.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if c.RateLimit != nil {
+			c.RateLimit.Wait()
+		}
+
+		req, err := http.NewRequest(method, c.endpoint+url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request to %s: %w", url, err)
+		}
+
+		resp, err := c.c.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute request to %s: %w", url, err)
+		}
+
+		if resp.StatusCode < 400 {
+			return c.readAndCacheResponse(resp, key, ttl, url)
+		}
+
+		apiErr := newAPIError(url, resp)
+		resp.Body.Close()
+
+		lastErr = apiErr
+
+		if attempt == maxAttempts || !apiErr.shouldRetry() {
+			return nil, apiErr
+		}
+
+		time.Sleep(c.Retry.retryDelay(resp, attempt))
+	}
+
+	return nil, lastErr
+}
+
+// readAndCacheResponse reads resp.Body and, if caching is enabled for this request, stores it
+// under key before returning it to the caller.
+func (c *Client) readAndCacheResponse(resp *http.Response, key string, ttl time.Duration, url string) (io.ReadCloser, error) {
+	if c.cache == nil || ttl <= 0 {
+		return resp.Body, nil
 	}
 
-	resp, err := c.c.Do(req)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request to %s: %w", url, err)
+		return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
 	}
 
-	return resp.Body, nil
+	c.cache.Set(key, body, ttl)
+
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
+
+// cacheTTL returns how long the response of the request to url should be cached for, based on
+// which endpoint it targets; a zero duration means the response should not be cached
+func (c *Client) cacheTTL(url string) time.Duration {
+	switch {
+	case strings.HasPrefix(url, "/locations/nearby"):
+		return c.StopsNearbyTTL
+	case strings.HasPrefix(url, "/locations"):
+		return c.LocationsTTL
+	case strings.HasPrefix(url, "/journeys"):
+		return c.JourneysTTL
+	case strings.Contains(url, "/departures"), strings.Contains(url, "/arrivals"):
+		return c.DeparturesTTL
+	default:
+		return 0
+	}
 }